@@ -0,0 +1,56 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command minio runs the S3-compatible HTTP API server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/minio-io/minio/pkg/auth"
+	"github.com/minio-io/minio/pkg/storage/drivers"
+	"github.com/minio-io/minio/pkg/webapi/minioapi"
+)
+
+func main() {
+	address := flag.String("address", ":8080", "address to serve the API on")
+	backend := flag.String("backend", "", "storage backend URI (s3://host, azure://account, gs://, or a filesystem path); defaults to $MINIO_BACKEND or ./minio-data")
+	flag.Parse()
+
+	backendURI := *backend
+	if backendURI == "" {
+		backendURI = os.Getenv("MINIO_BACKEND")
+	}
+	if backendURI == "" {
+		backendURI = "./minio-data"
+	}
+
+	storage, err := drivers.New(backendURI)
+	if err != nil {
+		log.Fatalf("minio: failed to initialize storage backend %q: %v", backendURI, err)
+	}
+
+	credentials := auth.NewCredentialStore()
+	if accessKey, secretKey := os.Getenv("MINIO_ACCESS_KEY"), os.Getenv("MINIO_SECRET_KEY"); accessKey != "" {
+		credentials.Add(accessKey, secretKey)
+	}
+
+	log.Printf("minio: serving %s on %s", backendURI, *address)
+	log.Fatal(http.ListenAndServe(*address, minioapi.HttpHandler(storage, credentials)))
+}
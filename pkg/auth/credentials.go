@@ -0,0 +1,60 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import "errors"
+
+// ErrNoSuchAccessKey is returned when a CredentialStore lookup finds no
+// matching access key.
+var ErrNoSuchAccessKey = errors.New("auth: no such access key")
+
+// Credential is an access-key/secret-key pair used to sign and verify
+// requests.
+type Credential struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// CredentialStore is an in-memory registry of known credentials, keyed by
+// access key. It is safe for concurrent reads; callers are expected to
+// populate it at startup before serving requests.
+type CredentialStore struct {
+	credentials map[string]Credential
+}
+
+// NewCredentialStore creates an empty CredentialStore.
+func NewCredentialStore() *CredentialStore {
+	return &CredentialStore{credentials: make(map[string]Credential)}
+}
+
+// Add registers a new access-key/secret-key pair.
+func (store *CredentialStore) Add(accessKeyID, secretAccessKey string) {
+	store.credentials[accessKeyID] = Credential{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	}
+}
+
+// SecretKey returns the secret key registered for accessKeyID, or
+// ErrNoSuchAccessKey if it is not known.
+func (store *CredentialStore) SecretKey(accessKeyID string) (string, error) {
+	credential, ok := store.credentials[accessKeyID]
+	if !ok {
+		return "", ErrNoSuchAccessKey
+	}
+	return credential.SecretAccessKey, nil
+}
@@ -0,0 +1,67 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// VerifyError wraps a request verification failure. Middleware does not
+// produce it: the error handed to ErrorHandler is the one returned by
+// SignatureV4Verifier.Verify, unwrapped, so it can be compared against the
+// package's Err* values directly.
+type VerifyError struct {
+	Err error
+}
+
+func (e *VerifyError) Error() string { return e.Err.Error() }
+
+// ErrorHandler is invoked by Middleware when request verification fails; it
+// is responsible for writing the HTTP response.
+type ErrorHandler func(w http.ResponseWriter, req *http.Request, err error)
+
+type contextKey int
+
+const accessKeyIDContextKey contextKey = 0
+
+// AccessKeyID returns the access key ID that signed req, as established by
+// Middleware. It returns "" if req never passed through Middleware.
+func AccessKeyID(req *http.Request) string {
+	accessKeyID, _ := req.Context().Value(accessKeyIDContextKey).(string)
+	return accessKeyID
+}
+
+// Middleware returns an http.Handler-wrapping middleware (compatible with
+// mux.MiddlewareFunc) that verifies every request's AWS Signature V4
+// signature before calling next. Verification failures are delegated to
+// onError instead of being handled inline, so the HTTP layer controls the
+// exact S3 error response. On success, the signing access key ID is stored
+// in the request context and can be read back with AccessKeyID.
+func Middleware(verifier *SignatureV4Verifier, onError ErrorHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			accessKeyID, err := verifier.Verify(req)
+			if err != nil {
+				onError(w, req, err)
+				return
+			}
+			ctx := context.WithValue(req.Context(), accessKeyIDContextKey, accessKeyID)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package policy models S3 bucket policy JSON documents and evaluates
+// whether a given principal/action/resource triple is allowed by one.
+package policy
+
+// Policy is an S3-style bucket policy document.
+type Policy struct {
+	Version   string      `json:"Version"`
+	Statement []Statement `json:"Statement"`
+}
+
+// Statement is a single policy statement. Principal, Action and Resource
+// each accept either a bare string or a list of strings in the JSON
+// document, hence the StringSet type.
+type Statement struct {
+	Sid       string            `json:"Sid,omitempty"`
+	Effect    string            `json:"Effect"`
+	Principal Principal         `json:"Principal"`
+	Action    StringSet         `json:"Action"`
+	Resource  StringSet         `json:"Resource"`
+	Condition map[string]string `json:"Condition,omitempty"`
+}
+
+// Principal identifies who a statement applies to. An AWS value of "*"
+// matches every principal.
+type Principal struct {
+	AWS StringSet `json:"AWS"`
+}
+
+// Effect values recognized in a Statement.
+const (
+	Allow = "Allow"
+	Deny  = "Deny"
+)
+
+// Allowed reports whether policy grants principal permission to perform
+// action against resource. A Deny statement always wins over an Allow;
+// absent any matching statement, the request is not allowed.
+func Allowed(p Policy, principal, action, resource string) bool {
+	allowed := false
+	for _, statement := range p.Statement {
+		if !statement.Principal.AWS.Matches(principal) {
+			continue
+		}
+		if !statement.Action.Matches(action) {
+			continue
+		}
+		if !statement.Resource.Matches(resource) {
+			continue
+		}
+		if statement.Effect == Deny {
+			return false
+		}
+		if statement.Effect == Allow {
+			allowed = true
+		}
+	}
+	return allowed
+}
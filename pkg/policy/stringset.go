@@ -0,0 +1,89 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StringSet holds a policy field that the S3 policy grammar allows to be
+// encoded as either a single JSON string or an array of strings.
+type StringSet []string
+
+// UnmarshalJSON accepts either a bare string or a []string.
+func (s *StringSet) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = StringSet{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*s = StringSet(many)
+	return nil
+}
+
+// MarshalJSON encodes a single-element set as a bare string, matching the
+// conventional S3 policy style, and a multi-element set as an array.
+func (s StringSet) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// Matches reports whether value matches any entry in the set, where an
+// entry may contain "*" as a wildcard matching any run of characters
+// (e.g. "s3:Get*" or "arn:aws:s3:::bucket/*").
+func (s StringSet) Matches(value string) bool {
+	for _, pattern := range s {
+		if matchWildcard(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard reports whether value matches pattern, where "*" in
+// pattern matches any (possibly empty) run of characters.
+func matchWildcard(pattern, value string) bool {
+	if pattern == "*" {
+		return true
+	}
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(value, part)
+		if idx < 0 {
+			return false
+		}
+		value = value[idx+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
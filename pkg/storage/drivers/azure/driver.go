@@ -0,0 +1,257 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package azure implements storage.Storage against Azure Blob Storage,
+// mapping buckets to containers and authenticating with a Shared Key.
+package azure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minio-io/minio/pkg/policy"
+	"github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/storage/drivers/remote"
+)
+
+type driver struct {
+	client    *remote.Client
+	multipart remote.MultipartStore
+	policies  remote.PolicyStore
+}
+
+// New constructs a Storage backend proxying an Azure Blob Storage account
+// (azure://account). Credentials are read from the AZURE_STORAGE_ACCOUNT
+// and AZURE_STORAGE_ACCESS_KEY environment variables.
+func New(uri *url.URL) (storage.Storage, error) {
+	account := uri.Host
+	if account == "" {
+		account = os.Getenv("AZURE_STORAGE_ACCOUNT")
+	}
+	accessKey := os.Getenv("AZURE_STORAGE_ACCESS_KEY")
+	if account == "" || accessKey == "" {
+		return nil, fmt.Errorf("azure: account and AZURE_STORAGE_ACCESS_KEY must be set")
+	}
+	key, err := base64.StdEncoding.DecodeString(accessKey)
+	if err != nil {
+		return nil, fmt.Errorf("azure: AZURE_STORAGE_ACCESS_KEY is not valid base64: %v", err)
+	}
+
+	client := &remote.Client{
+		Endpoint: fmt.Sprintf("https://%s.blob.core.windows.net", account),
+		Sign:     sign(account, key),
+	}
+	return &driver{
+		client:    client,
+		multipart: remote.MultipartStore{Client: client, Parse: parseListing},
+		policies:  remote.PolicyStore{Client: client},
+	}, nil
+}
+
+// sign returns a remote.Signer that adds a Shared Key Authorization header
+// covering the verb, content length, date and canonicalized resource path.
+func sign(account string, key []byte) remote.Signer {
+	return func(req *http.Request) {
+		date := time.Now().UTC().Format(time.RFC1123)
+		req.Header.Set("x-ms-date", date)
+		req.Header.Set("x-ms-version", "2020-10-02")
+
+		contentLength := ""
+		if req.ContentLength > 0 {
+			contentLength = strconv.FormatInt(req.ContentLength, 10)
+		}
+
+		stringToSign := req.Method + "\n" + // VERB
+			"\n" + // Content-Encoding
+			"\n" + // Content-Language
+			contentLength + "\n" + // Content-Length
+			"\n" + // Content-MD5
+			"\n" + // Content-Type
+			"\n" + // Date
+			"\n" + // If-Modified-Since
+			"\n" + // If-Match
+			"\n" + // If-None-Match
+			"\n" + // If-Unmodified-Since
+			"\n" + // Range
+			"x-ms-date:" + date + "\n" +
+			"x-ms-version:2020-10-02\n" +
+			"/" + account + req.URL.Path
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(stringToSign))
+		signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", account, signature))
+	}
+}
+
+func (d *driver) ListBuckets(prefix string) []storage.BucketMetadata {
+	// Listing containers requires a service-level call this path-style
+	// client does not make; containers are expected to be provisioned
+	// out of band for the Azure backend.
+	return nil
+}
+
+func (d *driver) StoreBucket(bucket string) error {
+	return d.client.Put(bucket, "", nil, 0)
+}
+
+func (d *driver) DeleteBucket(bucket string) error {
+	return d.client.Delete(bucket, "")
+}
+
+type enumerationResults struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string
+			Properties struct {
+				ContentLength int64 `xml:"Content-Length"`
+				Etag          string
+				LastModified  time.Time `xml:"Last-Modified"`
+			}
+		}
+	}
+}
+
+// parseListing decodes an Azure Blob Storage EnumerationResults XML
+// response into the flat, provider-agnostic shape remote.Paginate works
+// from.
+func parseListing(body io.Reader) ([]remote.Object, error) {
+	var result enumerationResults
+	if err := xml.NewDecoder(body).Decode(&result); err != nil {
+		return nil, err
+	}
+	entries := make([]remote.Object, len(result.Blobs.Blob))
+	for i, blob := range result.Blobs.Blob {
+		entries[i] = remote.Object{
+			Key:          blob.Name,
+			Size:         blob.Properties.ContentLength,
+			ETag:         blob.Properties.Etag,
+			LastModified: blob.Properties.LastModified,
+		}
+	}
+	return entries, nil
+}
+
+func (d *driver) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (storage.ListObjectsResult, error) {
+	body, err := d.client.List(bucket, prefix, maxKeys)
+	if err != nil {
+		return storage.ListObjectsResult{}, err
+	}
+	defer body.Close()
+
+	entries, err := parseListing(body)
+	if err != nil {
+		return storage.ListObjectsResult{}, err
+	}
+
+	objects := make([]storage.ObjectMetadata, len(entries))
+	for i, entry := range entries {
+		objects[i] = storage.ObjectMetadata{Key: entry.Key, Created: entry.LastModified, ETag: entry.ETag, Size: int(entry.Size)}
+	}
+	return remote.Paginate(objects, prefix, marker, delimiter, maxKeys), nil
+}
+
+func (d *driver) GetObjectMetadata(bucket, object string) (storage.ObjectMetadata, error) {
+	head, err := d.client.Head(bucket, object)
+	if err == remote.ErrNotFound {
+		return storage.ObjectMetadata{}, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	return storage.ObjectMetadata{Key: object, Created: head.LastModified, ETag: head.ETag, Size: int(head.Size)}, nil
+}
+
+func (d *driver) StoreObject(bucket, object string, data io.Reader) error {
+	buffered, size, err := remote.Buffer(data)
+	if err != nil {
+		return err
+	}
+	return d.client.Put(bucket, object, buffered, size)
+}
+
+func (d *driver) DeleteObject(bucket, object string) error {
+	err := d.client.Delete(bucket, object)
+	if err == remote.ErrNotFound {
+		return storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return err
+}
+
+func (d *driver) CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error) {
+	reader, err := d.client.Get(bucket, object)
+	if err == remote.ErrNotFound {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+func (d *driver) CopyObjectRangeToWriter(w io.Writer, bucket, object string, offset, length int64) (int64, error) {
+	reader, err := d.client.GetRange(bucket, object, offset, length)
+	if err == remote.ErrNotFound {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+func (d *driver) InitiateMultipartUpload(bucket, object string) (string, error) {
+	return d.multipart.InitiateMultipartUpload(bucket, object)
+}
+
+func (d *driver) PutObjectPart(bucket, object, uploadID string, partNumber int, body io.Reader) (string, error) {
+	return d.multipart.PutObjectPart(bucket, object, uploadID, partNumber, body)
+}
+
+func (d *driver) CompleteMultipartUpload(bucket, object, uploadID string, parts []storage.CompletedPart) (storage.ObjectMetadata, error) {
+	return d.multipart.CompleteMultipartUpload(bucket, object, uploadID, parts)
+}
+
+func (d *driver) AbortMultipartUpload(bucket, object, uploadID string) error {
+	return d.multipart.AbortMultipartUpload(bucket, object, uploadID)
+}
+
+func (d *driver) ListParts(bucket, object, uploadID string) ([]storage.ObjectPart, error) {
+	return d.multipart.ListParts(bucket, object, uploadID)
+}
+
+func (d *driver) GetBucketPolicy(bucket string) (policy.Policy, error) {
+	return d.policies.GetBucketPolicy(bucket)
+}
+
+func (d *driver) SetBucketPolicy(bucket string, p policy.Policy) error {
+	return d.policies.SetBucketPolicy(bucket, p)
+}
+
+func (d *driver) DeleteBucketPolicy(bucket string) error {
+	return d.policies.DeleteBucketPolicy(bucket)
+}
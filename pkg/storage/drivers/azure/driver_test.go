@@ -0,0 +1,100 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/storage/drivers/remote"
+	"github.com/minio-io/minio/pkg/storage/drivers/remote/remotetest"
+	"github.com/minio-io/minio/pkg/storage/storagetest"
+)
+
+const (
+	testAccount = "devstoreaccount1"
+)
+
+var testAccessKey = []byte("secretkeybytes")
+
+// checkSharedKeyAuth recomputes the Shared Key HMAC-SHA256 signature
+// sign() would have produced and compares it against the Authorization
+// header, so the conformance suite actually exercises this package's real
+// signer (including Content-Length) instead of bypassing it.
+func checkSharedKeyAuth(req *http.Request) error {
+	header := req.Header.Get("Authorization")
+	prefix := "SharedKey " + testAccount + ":"
+	if !strings.HasPrefix(header, prefix) {
+		return fmt.Errorf("azure: missing or malformed Authorization header %q", header)
+	}
+
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	stringToSign := req.Method + "\n" + // VERB
+		"\n" + // Content-Encoding
+		"\n" + // Content-Language
+		contentLength + "\n" + // Content-Length
+		"\n" + // Content-MD5
+		"\n" + // Content-Type
+		"\n" + // Date
+		"\n" + // If-Modified-Since
+		"\n" + // If-Match
+		"\n" + // If-None-Match
+		"\n" + // If-Unmodified-Since
+		"\n" + // Range
+		"x-ms-date:" + req.Header.Get("x-ms-date") + "\n" +
+		"x-ms-version:2020-10-02\n" +
+		"/" + testAccount + req.URL.Path
+
+	mac := hmac.New(sha256.New, testAccessKey)
+	mac.Write([]byte(stringToSign))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(strings.TrimPrefix(header, prefix)), []byte(expected)) {
+		return fmt.Errorf("azure: signature does not match")
+	}
+	return nil
+}
+
+// TestConformance runs the shared storage.Storage conformance suite
+// against this driver backed by a fake in-memory Azure-shaped HTTP server
+// that verifies every request's Shared Key signature, so this driver's
+// sign() is actually exercised rather than bypassed.
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Storage {
+		server := httptest.NewServer(remotetest.NewAuthenticatedServer(remotetest.RenderAzureListing, checkSharedKeyAuth))
+		t.Cleanup(server.Close)
+
+		client := &remote.Client{Endpoint: server.URL, Sign: sign(testAccount, testAccessKey)}
+		return &driver{
+			client:    client,
+			multipart: remote.MultipartStore{Client: client, Parse: parseListing},
+			policies:  remote.PolicyStore{Client: client},
+		}
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package drivers selects a concrete storage.Storage backend by URI
+// scheme, so the HTTP API can front a local filesystem or a remote object
+// store interchangeably.
+package drivers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/storage/drivers/azure"
+	"github.com/minio-io/minio/pkg/storage/drivers/fs"
+	"github.com/minio-io/minio/pkg/storage/drivers/gcs"
+	"github.com/minio-io/minio/pkg/storage/drivers/s3"
+)
+
+// New constructs a storage.Storage from uri, dispatching on its scheme:
+// "s3://host" for Amazon S3, "azure://account" for Azure Blob Storage,
+// "gs://" for Google Cloud Storage, and a bare filesystem path (no scheme,
+// or "file://path") for the local driver.
+func New(uri string) (storage.Storage, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsed.Scheme {
+	case "s3":
+		return s3.New(parsed)
+	case "azure":
+		return azure.New(parsed)
+	case "gs":
+		return gcs.New(parsed)
+	case "", "file":
+		return fs.New(parsed.Path)
+	default:
+		return nil, fmt.Errorf("drivers: unsupported backend scheme %q", parsed.Scheme)
+	}
+}
@@ -0,0 +1,386 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fs implements storage.Storage directly on top of the local
+// filesystem: buckets are directories under a root, objects are files
+// within them.
+package fs
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio-io/minio/pkg/storage"
+)
+
+type driver struct {
+	root string
+}
+
+// New constructs a filesystem-backed Storage rooted at dir, creating it if
+// it does not already exist.
+func New(dir string) (storage.Storage, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &driver{root: dir}, nil
+}
+
+func (d *driver) bucketPath(bucket string) string {
+	return filepath.Join(d.root, bucket)
+}
+
+func (d *driver) objectPath(bucket, object string) string {
+	return filepath.Join(d.bucketPath(bucket), object)
+}
+
+func (d *driver) uploadDir(bucket, object, uploadID string) string {
+	return filepath.Join(d.root, ".multipart", bucket, object, uploadID)
+}
+
+func (d *driver) ListBuckets(prefix string) []storage.BucketMetadata {
+	entries, err := ioutil.ReadDir(d.root)
+	if err != nil {
+		return nil
+	}
+	var buckets []storage.BucketMetadata
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		buckets = append(buckets, storage.BucketMetadata{
+			Name:    entry.Name(),
+			Created: entry.ModTime(),
+		})
+	}
+	return buckets
+}
+
+func (d *driver) StoreBucket(bucket string) error {
+	return os.MkdirAll(d.bucketPath(bucket), 0700)
+}
+
+func (d *driver) DeleteBucket(bucket string) error {
+	if _, err := os.Stat(d.bucketPath(bucket)); os.IsNotExist(err) {
+		return storage.BucketNotFound{Bucket: bucket}
+	}
+	return os.Remove(d.bucketPath(bucket))
+}
+
+func (d *driver) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (storage.ListObjectsResult, error) {
+	var objects []storage.ObjectMetadata
+	filepath.Walk(d.bucketPath(bucket), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(d.bucketPath(bucket), path)
+		relative = filepath.ToSlash(relative)
+		if err != nil || !strings.HasPrefix(relative, prefix) || relative <= marker {
+			return nil
+		}
+		metadata, err := d.metadataFor(bucket, relative, info)
+		if err != nil {
+			return nil
+		}
+		objects = append(objects, metadata)
+		return nil
+	})
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	var result storage.ListObjectsResult
+	seenPrefixes := make(map[string]bool)
+	for _, object := range objects {
+		if delimiter != "" {
+			if rest := strings.TrimPrefix(object.Key, prefix); strings.Contains(rest, delimiter) {
+				commonPrefix := prefix + rest[:strings.Index(rest, delimiter)+len(delimiter)]
+				if !seenPrefixes[commonPrefix] && commonPrefix > marker {
+					seenPrefixes[commonPrefix] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix)
+				}
+				continue
+			}
+		}
+		result.Objects = append(result.Objects, object)
+	}
+
+	entryCount := len(result.Objects) + len(result.CommonPrefixes)
+	if maxKeys > 0 && entryCount > maxKeys {
+		result.IsTruncated = true
+		result.Objects, result.CommonPrefixes, result.NextMarker = truncateListing(result.Objects, result.CommonPrefixes, maxKeys)
+	}
+	return result, nil
+}
+
+// truncateListing trims objects and commonPrefixes, interleaved in key
+// order, down to maxKeys total entries, and reports nextMarker as whichever
+// of the two was actually served last.
+func truncateListing(objects []storage.ObjectMetadata, commonPrefixes []string, maxKeys int) (truncatedObjects []storage.ObjectMetadata, truncatedPrefixes []string, nextMarker string) {
+	i, j := 0, 0
+	for len(truncatedObjects)+len(truncatedPrefixes) < maxKeys && (i < len(objects) || j < len(commonPrefixes)) {
+		switch {
+		case i >= len(objects):
+			truncatedPrefixes = append(truncatedPrefixes, commonPrefixes[j])
+			nextMarker = commonPrefixes[j]
+			j++
+		case j >= len(commonPrefixes):
+			truncatedObjects = append(truncatedObjects, objects[i])
+			nextMarker = objects[i].Key
+			i++
+		case objects[i].Key < commonPrefixes[j]:
+			truncatedObjects = append(truncatedObjects, objects[i])
+			nextMarker = objects[i].Key
+			i++
+		default:
+			truncatedPrefixes = append(truncatedPrefixes, commonPrefixes[j])
+			nextMarker = commonPrefixes[j]
+			j++
+		}
+	}
+	return truncatedObjects, truncatedPrefixes, nextMarker
+}
+
+func (d *driver) metadataFor(bucket, object string, info os.FileInfo) (storage.ObjectMetadata, error) {
+	etag, err := md5File(filepath.Join(d.bucketPath(bucket), object))
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	return storage.ObjectMetadata{
+		Key:     object,
+		Created: info.ModTime(),
+		ETag:    etag,
+		Size:    int(info.Size()),
+	}, nil
+}
+
+func (d *driver) GetObjectMetadata(bucket, object string) (storage.ObjectMetadata, error) {
+	info, err := os.Stat(d.objectPath(bucket, object))
+	if os.IsNotExist(err) {
+		return storage.ObjectMetadata{}, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	return d.metadataFor(bucket, object, info)
+}
+
+func (d *driver) StoreObject(bucket, object string, data io.Reader) error {
+	path := d.objectPath(bucket, object)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, data)
+	return err
+}
+
+func (d *driver) DeleteObject(bucket, object string) error {
+	err := os.Remove(d.objectPath(bucket, object))
+	if os.IsNotExist(err) {
+		return storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return err
+}
+
+func (d *driver) CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error) {
+	file, err := os.Open(d.objectPath(bucket, object))
+	if os.IsNotExist(err) {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return io.Copy(w, file)
+}
+
+func (d *driver) CopyObjectRangeToWriter(w io.Writer, bucket, object string, offset, length int64) (int64, error) {
+	file, err := os.Open(d.objectPath(bucket, object))
+	if os.IsNotExist(err) {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 || offset+length > info.Size() {
+		return 0, storage.InvalidRange{Bucket: bucket, Object: object, Size: int(info.Size())}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.CopyN(w, file, length)
+}
+
+func (d *driver) InitiateMultipartUpload(bucket, object string) (string, error) {
+	uploadID := fmt.Sprintf("%d", time.Now().UnixNano())
+	if err := os.MkdirAll(d.uploadDir(bucket, object, uploadID), 0700); err != nil {
+		return "", err
+	}
+	return uploadID, nil
+}
+
+func (d *driver) partPath(bucket, object, uploadID string, partNumber int) string {
+	return filepath.Join(d.uploadDir(bucket, object, uploadID), strconv.Itoa(partNumber))
+}
+
+func (d *driver) PutObjectPart(bucket, object, uploadID string, partNumber int, body io.Reader) (string, error) {
+	dir := d.uploadDir(bucket, object, uploadID)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", storage.UploadNotFound{Bucket: bucket, Object: object, UploadID: uploadID}
+	}
+	path := d.partPath(bucket, object, uploadID, partNumber)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := md5.New()
+	if _, err := io.Copy(io.MultiWriter(file, hasher), body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (d *driver) CompleteMultipartUpload(bucket, object, uploadID string, parts []storage.CompletedPart) (storage.ObjectMetadata, error) {
+	dir := d.uploadDir(bucket, object, uploadID)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return storage.ObjectMetadata{}, storage.UploadNotFound{Bucket: bucket, Object: object, UploadID: uploadID}
+	}
+	if err := storage.ValidatePartOrder(bucket, object, uploadID, parts); err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+
+	path := d.objectPath(bucket, object)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+
+	// The final ETag is the MD5 of the concatenated raw MD5 bytes of
+	// every part, with a "-<n>" suffix, matching S3 multipart semantics.
+	// The client-supplied part.ETag is only trusted once it has been
+	// checked against the MD5 actually stored for that part, so a client
+	// can't claim content it didn't upload.
+	digests := md5.New()
+	for _, part := range parts {
+		partFile, err := os.Open(d.partPath(bucket, object, uploadID, part.PartNumber))
+		if err != nil {
+			file.Close()
+			return storage.ObjectMetadata{}, err
+		}
+		hasher := md5.New()
+		if _, err := io.Copy(io.MultiWriter(file, hasher), partFile); err != nil {
+			partFile.Close()
+			file.Close()
+			return storage.ObjectMetadata{}, err
+		}
+		partFile.Close()
+
+		actualETag := hex.EncodeToString(hasher.Sum(nil))
+		if part.ETag != actualETag {
+			file.Close()
+			return storage.ObjectMetadata{}, storage.InvalidPart{Bucket: bucket, Object: object, UploadID: uploadID, PartNumber: part.PartNumber}
+		}
+		digests.Write(hasher.Sum(nil))
+	}
+	file.Close()
+	os.RemoveAll(dir)
+
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(digests.Sum(nil)), len(parts))
+	info, err := os.Stat(path)
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	return storage.ObjectMetadata{
+		Key:     object,
+		Created: info.ModTime(),
+		ETag:    etag,
+		Size:    int(info.Size()),
+	}, nil
+}
+
+func (d *driver) AbortMultipartUpload(bucket, object, uploadID string) error {
+	dir := d.uploadDir(bucket, object, uploadID)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return storage.UploadNotFound{Bucket: bucket, Object: object, UploadID: uploadID}
+	}
+	return os.RemoveAll(dir)
+}
+
+func (d *driver) ListParts(bucket, object, uploadID string) ([]storage.ObjectPart, error) {
+	dir := d.uploadDir(bucket, object, uploadID)
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, storage.UploadNotFound{Bucket: bucket, Object: object, UploadID: uploadID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []storage.ObjectPart
+	for _, entry := range entries {
+		partNumber, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		etag, err := md5File(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, storage.ObjectPart{
+			PartNumber:   partNumber,
+			ETag:         etag,
+			Size:         int(entry.Size()),
+			LastModified: entry.ModTime(),
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+func md5File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := md5.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
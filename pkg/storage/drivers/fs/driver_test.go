@@ -0,0 +1,124 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Storage {
+		s, err := New(t.TempDir())
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	})
+}
+
+// TestListObjectsNextMarkerTracksLastServedKey covers the case where the
+// last entry served on a truncated page is a common prefix, not an
+// object: NextMarker must be that prefix, not the last object key, even
+// though Objects is non-empty.
+func TestListObjectsNextMarkerTracksLastServedKey(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	const bucket = "bucket"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+	for _, key := range []string{"a", "b0/x", "b1/x"} {
+		if err := s.StoreObject(bucket, key, strings.NewReader(key)); err != nil {
+			t.Fatalf("StoreObject(%q): %v", key, err)
+		}
+	}
+
+	result, err := s.ListObjects(bucket, "", "", "/", 2)
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	if !result.IsTruncated || result.NextMarker != "b0/" {
+		t.Fatalf("ListObjects = %+v, want IsTruncated=true NextMarker=\"b0/\"", result)
+	}
+}
+
+// TestCompleteMultipartUploadRejectsForgedETag covers a client reporting
+// an arbitrary (but valid-hex) ETag for a part instead of the MD5 actually
+// stored for it.
+func TestCompleteMultipartUploadRejectsForgedETag(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	const bucket, object = "bucket", "object"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+	uploadID, err := s.InitiateMultipartUpload(bucket, object)
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload: %v", err)
+	}
+	if _, err := s.PutObjectPart(bucket, object, uploadID, 1, strings.NewReader("part one")); err != nil {
+		t.Fatalf("PutObjectPart: %v", err)
+	}
+
+	forgedETag := "00000000000000000000000000000000"
+	_, err = s.CompleteMultipartUpload(bucket, object, uploadID, []storage.CompletedPart{{PartNumber: 1, ETag: forgedETag}})
+	if _, ok := err.(storage.InvalidPart); !ok {
+		t.Fatalf("CompleteMultipartUpload error = %v, want storage.InvalidPart", err)
+	}
+}
+
+// TestCompleteMultipartUploadRejectsOutOfOrderParts covers a parts list
+// that is not strictly ascending by PartNumber, which would otherwise
+// silently concatenate the parts in the wrong order.
+func TestCompleteMultipartUploadRejectsOutOfOrderParts(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	const bucket, object = "bucket", "object"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+	uploadID, err := s.InitiateMultipartUpload(bucket, object)
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload: %v", err)
+	}
+	etag1, err := s.PutObjectPart(bucket, object, uploadID, 1, strings.NewReader("part one"))
+	if err != nil {
+		t.Fatalf("PutObjectPart(1): %v", err)
+	}
+	etag2, err := s.PutObjectPart(bucket, object, uploadID, 2, strings.NewReader("part two"))
+	if err != nil {
+		t.Fatalf("PutObjectPart(2): %v", err)
+	}
+
+	_, err = s.CompleteMultipartUpload(bucket, object, uploadID, []storage.CompletedPart{
+		{PartNumber: 2, ETag: etag2},
+		{PartNumber: 1, ETag: etag1},
+	})
+	if _, ok := err.(storage.InvalidPartOrder); !ok {
+		t.Fatalf("CompleteMultipartUpload error = %v, want storage.InvalidPartOrder", err)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minio-io/minio/pkg/policy"
+	"github.com/minio-io/minio/pkg/storage"
+)
+
+// policyPath returns where bucket's policy document is stored. Policies
+// live outside the bucket directory so they never show up as an object in
+// ListObjects.
+func (d *driver) policyPath(bucket string) string {
+	return filepath.Join(d.root, ".policies", bucket+".json")
+}
+
+func (d *driver) GetBucketPolicy(bucket string) (policy.Policy, error) {
+	data, err := ioutil.ReadFile(d.policyPath(bucket))
+	if os.IsNotExist(err) {
+		return policy.Policy{}, storage.PolicyNotFound{Bucket: bucket}
+	}
+	if err != nil {
+		return policy.Policy{}, err
+	}
+
+	var p policy.Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return policy.Policy{}, err
+	}
+	return p, nil
+}
+
+func (d *driver) SetBucketPolicy(bucket string, p policy.Policy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	path := d.policyPath(bucket)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func (d *driver) DeleteBucketPolicy(bucket string) error {
+	err := os.Remove(d.policyPath(bucket))
+	if os.IsNotExist(err) {
+		return storage.PolicyNotFound{Bucket: bucket}
+	}
+	return err
+}
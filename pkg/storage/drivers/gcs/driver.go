@@ -0,0 +1,221 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gcs implements storage.Storage against Google Cloud Storage's
+// XML interoperability API, which mirrors S3's request shape closely
+// enough to reuse the same HMAC-style signing and listing format.
+package gcs
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/minio-io/minio/pkg/policy"
+	"github.com/minio-io/minio/pkg/storage"
+	"github.com/minio-io/minio/pkg/storage/drivers/remote"
+)
+
+type driver struct {
+	client    *remote.Client
+	multipart remote.MultipartStore
+	policies  remote.PolicyStore
+}
+
+// New constructs a Storage backend proxying Google Cloud Storage's XML
+// API. Credentials are read from the GOOGLE_STORAGE_ACCESS_KEY_ID and
+// GOOGLE_STORAGE_SECRET environment variables (GCS interoperable HMAC
+// credentials).
+func New(uri *url.URL) (storage.Storage, error) {
+	accessKey := os.Getenv("GOOGLE_STORAGE_ACCESS_KEY_ID")
+	secretKey := os.Getenv("GOOGLE_STORAGE_SECRET")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("gcs: GOOGLE_STORAGE_ACCESS_KEY_ID/GOOGLE_STORAGE_SECRET must be set")
+	}
+
+	client := &remote.Client{
+		Endpoint: "https://storage.googleapis.com",
+		Sign:     sign(accessKey, secretKey),
+	}
+	return &driver{
+		client:    client,
+		multipart: remote.MultipartStore{Client: client, Parse: parseListing},
+		policies:  remote.PolicyStore{Client: client},
+	}, nil
+}
+
+// sign returns a remote.Signer implementing the GCS XML API's HMAC
+// authentication scheme: an HMAC-SHA1 over the verb, date and resource
+// path, sent as an "Authorization: GOOG1 accessKey:signature" header.
+func sign(accessKey, secretKey string) remote.Signer {
+	return func(req *http.Request) {
+		date := time.Now().UTC().Format(time.RFC1123)
+		req.Header.Set("Date", date)
+
+		stringToSign := req.Method + "\n\n\n" + date + "\n" + req.URL.Path
+		mac := hmac.New(sha1.New, []byte(secretKey))
+		mac.Write([]byte(stringToSign))
+		signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		req.Header.Set("Authorization", fmt.Sprintf("GOOG1 %s:%s", accessKey, signature))
+	}
+}
+
+func (d *driver) ListBuckets(prefix string) []storage.BucketMetadata {
+	// Listing buckets requires a project-scoped JSON API call this
+	// path-style XML client does not make; buckets are expected to be
+	// provisioned out of band for the GCS backend.
+	return nil
+}
+
+func (d *driver) StoreBucket(bucket string) error {
+	return d.client.Put(bucket, "", nil, 0)
+}
+
+func (d *driver) DeleteBucket(bucket string) error {
+	return d.client.Delete(bucket, "")
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		ETag         string
+		LastModified time.Time
+	}
+}
+
+// parseListing decodes a GCS XML API ListBucketResult response into the
+// flat, provider-agnostic shape remote.Paginate works from.
+func parseListing(body io.Reader) ([]remote.Object, error) {
+	var result listBucketResult
+	if err := xml.NewDecoder(body).Decode(&result); err != nil {
+		return nil, err
+	}
+	entries := make([]remote.Object, len(result.Contents))
+	for i, entry := range result.Contents {
+		entries[i] = remote.Object{Key: entry.Key, Size: entry.Size, ETag: entry.ETag, LastModified: entry.LastModified}
+	}
+	return entries, nil
+}
+
+func (d *driver) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (storage.ListObjectsResult, error) {
+	body, err := d.client.List(bucket, prefix, maxKeys)
+	if err != nil {
+		return storage.ListObjectsResult{}, err
+	}
+	defer body.Close()
+
+	entries, err := parseListing(body)
+	if err != nil {
+		return storage.ListObjectsResult{}, err
+	}
+
+	objects := make([]storage.ObjectMetadata, len(entries))
+	for i, entry := range entries {
+		objects[i] = storage.ObjectMetadata{Key: entry.Key, Created: entry.LastModified, ETag: entry.ETag, Size: int(entry.Size)}
+	}
+	return remote.Paginate(objects, prefix, marker, delimiter, maxKeys), nil
+}
+
+func (d *driver) GetObjectMetadata(bucket, object string) (storage.ObjectMetadata, error) {
+	head, err := d.client.Head(bucket, object)
+	if err == remote.ErrNotFound {
+		return storage.ObjectMetadata{}, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	return storage.ObjectMetadata{Key: object, Created: head.LastModified, ETag: head.ETag, Size: int(head.Size)}, nil
+}
+
+func (d *driver) StoreObject(bucket, object string, data io.Reader) error {
+	buffered, size, err := remote.Buffer(data)
+	if err != nil {
+		return err
+	}
+	return d.client.Put(bucket, object, buffered, size)
+}
+
+func (d *driver) DeleteObject(bucket, object string) error {
+	err := d.client.Delete(bucket, object)
+	if err == remote.ErrNotFound {
+		return storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return err
+}
+
+func (d *driver) CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error) {
+	reader, err := d.client.Get(bucket, object)
+	if err == remote.ErrNotFound {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+func (d *driver) CopyObjectRangeToWriter(w io.Writer, bucket, object string, offset, length int64) (int64, error) {
+	reader, err := d.client.GetRange(bucket, object, offset, length)
+	if err == remote.ErrNotFound {
+		return 0, storage.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return io.Copy(w, reader)
+}
+
+func (d *driver) InitiateMultipartUpload(bucket, object string) (string, error) {
+	return d.multipart.InitiateMultipartUpload(bucket, object)
+}
+
+func (d *driver) PutObjectPart(bucket, object, uploadID string, partNumber int, body io.Reader) (string, error) {
+	return d.multipart.PutObjectPart(bucket, object, uploadID, partNumber, body)
+}
+
+func (d *driver) CompleteMultipartUpload(bucket, object, uploadID string, parts []storage.CompletedPart) (storage.ObjectMetadata, error) {
+	return d.multipart.CompleteMultipartUpload(bucket, object, uploadID, parts)
+}
+
+func (d *driver) AbortMultipartUpload(bucket, object, uploadID string) error {
+	return d.multipart.AbortMultipartUpload(bucket, object, uploadID)
+}
+
+func (d *driver) ListParts(bucket, object, uploadID string) ([]storage.ObjectPart, error) {
+	return d.multipart.ListParts(bucket, object, uploadID)
+}
+
+func (d *driver) GetBucketPolicy(bucket string) (policy.Policy, error) {
+	return d.policies.GetBucketPolicy(bucket)
+}
+
+func (d *driver) SetBucketPolicy(bucket string, p policy.Policy) error {
+	return d.policies.SetBucketPolicy(bucket, p)
+}
+
+func (d *driver) DeleteBucketPolicy(bucket string) error {
+	return d.policies.DeleteBucketPolicy(bucket)
+}
@@ -0,0 +1,393 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package remote implements a small path-style REST client shared by the
+// S3, Azure Blob and GCS drivers. Each of those backends speaks a similar
+// enough subset of HTTP (PUT to write, GET/HEAD to read, DELETE to remove,
+// a listing GET to enumerate) that the protocol differences can be
+// isolated to endpoint construction and request signing, which each
+// driver supplies via a Signer.
+package remote
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio-io/minio/pkg/policy"
+	"github.com/minio-io/minio/pkg/storage"
+)
+
+// Signer authenticates an outgoing request to the remote object store,
+// typically by adding an Authorization (or equivalent) header.
+type Signer func(req *http.Request)
+
+// Object is a minimal listing/head entry as returned by the remote store.
+type Object struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// ErrNotFound is returned by Get/GetRange/Head when the remote store
+// reports the key does not exist. Delete treats a 404 as success and
+// returns nil.
+var ErrNotFound = fmt.Errorf("remote: key not found")
+
+// EntryParser decodes the body of a List response, which is in whatever
+// wire format the remote provider uses (S3 and GCS XML, Azure XML with a
+// different shape, ...), into a flat slice of Objects.
+type EntryParser func(body io.Reader) ([]Object, error)
+
+// Client is a minimal path-style REST client: requests are addressed as
+// Endpoint/bucket/key and signed with Sign before being sent.
+type Client struct {
+	Endpoint string
+	Sign     Signer
+	HTTP     *http.Client
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	c.Sign(req)
+	return c.httpClient().Do(req)
+}
+
+func (c *Client) url(bucket, key string) string {
+	if key == "" {
+		return c.Endpoint + "/" + bucket
+	}
+	return c.Endpoint + "/" + bucket + "/" + key
+}
+
+// Buffer reads r fully into memory and returns a Reader over it along with
+// its size, since Put needs a known Content-Length up front and data
+// arriving from an HTTP request body has none.
+func Buffer(r io.Reader) (io.Reader, int64, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// Put uploads body as bucket/key.
+func (c *Client) Put(bucket, key string, body io.Reader, size int64) error {
+	req, err := http.NewRequest("PUT", c.url(bucket, key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("remote: PUT %s: %s", key, resp.Status)
+	}
+	return nil
+}
+
+// Get fetches bucket/key in full.
+func (c *Client) Get(bucket, key string) (io.ReadCloser, error) {
+	return c.GetRange(bucket, key, 0, -1)
+}
+
+// GetRange fetches length bytes of bucket/key starting at offset. A
+// negative length fetches to the end of the object.
+func (c *Client) GetRange(bucket, key string, offset, length int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", c.url(bucket, key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset != 0 || length >= 0 {
+		if length < 0 {
+			req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		} else {
+			req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-"+strconv.FormatInt(offset+length-1, 10))
+		}
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("remote: GET %s: %s", key, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Head returns metadata for bucket/key without fetching its body.
+func (c *Client) Head(bucket, key string) (Object, error) {
+	req, err := http.NewRequest("HEAD", c.url(bucket, key), nil)
+	if err != nil {
+		return Object{}, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return Object{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return Object{}, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return Object{}, fmt.Errorf("remote: HEAD %s: %s", key, resp.Status)
+	}
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	lastModified, _ := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
+	return Object{
+		Key:          key,
+		Size:         size,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: lastModified,
+	}, nil
+}
+
+// Delete removes bucket/key.
+func (c *Client) Delete(bucket, key string) error {
+	req, err := http.NewRequest("DELETE", c.url(bucket, key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("remote: DELETE %s: %s", key, resp.Status)
+	}
+	return nil
+}
+
+// List enumerates objects in bucket whose key starts with prefix, up to
+// maxKeys entries (a non-positive maxKeys asks the provider for its
+// default page size). Listing wire formats differ enough between
+// providers (S3 XML, Azure XML, GCS XML) that each driver parses the
+// response body itself; List issues the request and hands back the raw
+// body.
+func (c *Client) List(bucket, prefix string, maxKeys int) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", c.url(bucket, ""), nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("prefix", prefix)
+	if maxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(maxKeys))
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("remote: LIST %s: %s", bucket, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// MultipartStore bridges storage.Storage's multipart-upload methods onto a
+// Client that has no native multipart API of its own: parts are buffered
+// as ordinary objects under a reserved key prefix and concatenated into
+// the final object on completion. Drivers with a native multipart API
+// should prefer it over this bridge; MultipartStore exists so every
+// backend can satisfy storage.Storage from day one.
+type MultipartStore struct {
+	Client *Client
+	// Parse decodes the provider-specific List response body so ListParts
+	// can enumerate the parts buffered under the reserved prefix.
+	Parse EntryParser
+}
+
+// uploadsPrefix is the reserved key prefix buffered multipart parts are
+// stored under, kept out of ListObjects by remote.Paginate the same way
+// policyKey is.
+const uploadsPrefix = ".uploads/"
+
+func (m MultipartStore) partPrefix(object, uploadID string) string {
+	return uploadsPrefix + object + "/" + uploadID + "/"
+}
+
+func (m MultipartStore) partKey(object, uploadID string, partNumber int) string {
+	return m.partPrefix(object, uploadID) + strconv.Itoa(partNumber)
+}
+
+func (m MultipartStore) InitiateMultipartUpload(bucket, object string) (string, error) {
+	return fmt.Sprintf("%d", time.Now().UnixNano()), nil
+}
+
+func (m MultipartStore) PutObjectPart(bucket, object, uploadID string, partNumber int, body io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	hash := md5.Sum(data)
+	if err := m.Client.Put(bucket, m.partKey(object, uploadID, partNumber), bytes.NewReader(data), int64(len(data))); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (m MultipartStore) CompleteMultipartUpload(bucket, object, uploadID string, parts []storage.CompletedPart) (storage.ObjectMetadata, error) {
+	if err := storage.ValidatePartOrder(bucket, object, uploadID, parts); err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+
+	// The client-supplied part.ETag is only trusted once it has been
+	// checked against the MD5 actually stored for that part, so a client
+	// can't claim content it didn't upload.
+	digests := md5.New()
+	var body bytes.Buffer
+	for _, part := range parts {
+		reader, err := m.Client.Get(bucket, m.partKey(object, uploadID, part.PartNumber))
+		if err != nil {
+			return storage.ObjectMetadata{}, err
+		}
+		hasher := md5.New()
+		_, err = io.Copy(io.MultiWriter(&body, hasher), reader)
+		reader.Close()
+		if err != nil {
+			return storage.ObjectMetadata{}, err
+		}
+		actualETag := hex.EncodeToString(hasher.Sum(nil))
+		if part.ETag != actualETag {
+			return storage.ObjectMetadata{}, storage.InvalidPart{Bucket: bucket, Object: object, UploadID: uploadID, PartNumber: part.PartNumber}
+		}
+		digests.Write(hasher.Sum(nil))
+	}
+
+	if err := m.Client.Put(bucket, object, bytes.NewReader(body.Bytes()), int64(body.Len())); err != nil {
+		return storage.ObjectMetadata{}, err
+	}
+	for _, part := range parts {
+		m.Client.Delete(bucket, m.partKey(object, uploadID, part.PartNumber))
+	}
+
+	return storage.ObjectMetadata{
+		Key:     object,
+		Created: time.Now(),
+		ETag:    fmt.Sprintf("%s-%d", hex.EncodeToString(digests.Sum(nil)), len(parts)),
+		Size:    body.Len(),
+	}, nil
+}
+
+func (m MultipartStore) AbortMultipartUpload(bucket, object, uploadID string) error {
+	parts, err := m.ListParts(bucket, object, uploadID)
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		m.Client.Delete(bucket, m.partKey(object, uploadID, part.PartNumber))
+	}
+	return nil
+}
+
+func (m MultipartStore) ListParts(bucket, object, uploadID string) ([]storage.ObjectPart, error) {
+	prefix := m.partPrefix(object, uploadID)
+	body, err := m.Client.List(bucket, prefix, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	entries, err := m.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []storage.ObjectPart
+	for _, entry := range entries {
+		partNumber, err := strconv.Atoi(strings.TrimPrefix(entry.Key, prefix))
+		if err != nil {
+			continue
+		}
+		parts = append(parts, storage.ObjectPart{
+			PartNumber:   partNumber,
+			ETag:         entry.ETag,
+			Size:         int(entry.Size),
+			LastModified: entry.LastModified,
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// policyKey is the reserved object key a PolicyStore persists a bucket's
+// policy document under.
+const policyKey = ".policy.json"
+
+// PolicyStore bridges storage.Storage's bucket-policy methods onto a
+// Client by storing the policy document as an ordinary object under a
+// reserved key, for backends with no native bucket-policy API of their
+// own.
+type PolicyStore struct {
+	Client *Client
+}
+
+func (p PolicyStore) GetBucketPolicy(bucket string) (policy.Policy, error) {
+	reader, err := p.Client.Get(bucket, policyKey)
+	if err == ErrNotFound {
+		return policy.Policy{}, storage.PolicyNotFound{Bucket: bucket}
+	}
+	if err != nil {
+		return policy.Policy{}, err
+	}
+	defer reader.Close()
+
+	var doc policy.Policy
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return policy.Policy{}, err
+	}
+	return doc, nil
+}
+
+func (p PolicyStore) SetBucketPolicy(bucket string, doc policy.Policy) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return p.Client.Put(bucket, policyKey, bytes.NewReader(data), int64(len(data)))
+}
+
+func (p PolicyStore) DeleteBucketPolicy(bucket string) error {
+	return p.Client.Delete(bucket, policyKey)
+}
@@ -0,0 +1,101 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/minio-io/minio/pkg/storage"
+)
+
+// isReservedKey reports whether key is one PolicyStore or MultipartStore
+// writes into the bucket's own key namespace (the bucket-policy document
+// or a buffered multipart part) rather than real object data, and so must
+// never appear in a listing.
+func isReservedKey(key string) bool {
+	return key == policyKey || strings.HasPrefix(key, uploadsPrefix)
+}
+
+// Paginate turns a flat, prefix-filtered list of objects into a
+// storage.ListObjectsResult: resuming after marker, rolling up keys that
+// contain delimiter past prefix into CommonPrefixes, and truncating to
+// maxKeys. Every driver built on this package's Client shares this logic
+// so each only has to parse its own wire format into []storage.ObjectMetadata.
+// Reserved keys that PolicyStore/MultipartStore write into the same
+// bucket namespace are excluded, the same way the fs driver keeps them
+// out of the bucket directory entirely.
+func Paginate(objects []storage.ObjectMetadata, prefix, marker, delimiter string, maxKeys int) storage.ListObjectsResult {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	var result storage.ListObjectsResult
+	seenPrefixes := make(map[string]bool)
+	for _, object := range objects {
+		if isReservedKey(object.Key) {
+			continue
+		}
+		if object.Key <= marker {
+			continue
+		}
+		if delimiter != "" {
+			if rest := strings.TrimPrefix(object.Key, prefix); strings.Contains(rest, delimiter) {
+				commonPrefix := prefix + rest[:strings.Index(rest, delimiter)+len(delimiter)]
+				if !seenPrefixes[commonPrefix] && commonPrefix > marker {
+					seenPrefixes[commonPrefix] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix)
+				}
+				continue
+			}
+		}
+		result.Objects = append(result.Objects, object)
+	}
+
+	entryCount := len(result.Objects) + len(result.CommonPrefixes)
+	if maxKeys > 0 && entryCount > maxKeys {
+		result.IsTruncated = true
+		result.Objects, result.CommonPrefixes, result.NextMarker = truncateListing(result.Objects, result.CommonPrefixes, maxKeys)
+	}
+	return result
+}
+
+// truncateListing trims objects and commonPrefixes, interleaved in key
+// order, down to maxKeys total entries, and reports nextMarker as whichever
+// of the two was actually served last.
+func truncateListing(objects []storage.ObjectMetadata, commonPrefixes []string, maxKeys int) (truncatedObjects []storage.ObjectMetadata, truncatedPrefixes []string, nextMarker string) {
+	i, j := 0, 0
+	for len(truncatedObjects)+len(truncatedPrefixes) < maxKeys && (i < len(objects) || j < len(commonPrefixes)) {
+		switch {
+		case i >= len(objects):
+			truncatedPrefixes = append(truncatedPrefixes, commonPrefixes[j])
+			nextMarker = commonPrefixes[j]
+			j++
+		case j >= len(commonPrefixes):
+			truncatedObjects = append(truncatedObjects, objects[i])
+			nextMarker = objects[i].Key
+			i++
+		case objects[i].Key < commonPrefixes[j]:
+			truncatedObjects = append(truncatedObjects, objects[i])
+			nextMarker = objects[i].Key
+			i++
+		default:
+			truncatedPrefixes = append(truncatedPrefixes, commonPrefixes[j])
+			nextMarker = commonPrefixes[j]
+			j++
+		}
+	}
+	return truncatedObjects, truncatedPrefixes, nextMarker
+}
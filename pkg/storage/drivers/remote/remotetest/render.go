@@ -0,0 +1,59 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remotetest
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RenderS3Listing renders entries as the S3 (and GCS XML API, which
+// mirrors it) ListBucketResult body.
+func RenderS3Listing(entries []Entry) string {
+	var buf strings.Builder
+	buf.WriteString("<ListBucketResult>")
+	for _, entry := range entries {
+		buf.WriteString("<Contents>")
+		buf.WriteString("<Key>" + entry.Key + "</Key>")
+		buf.WriteString("<Size>" + strconv.FormatInt(entry.Size, 10) + "</Size>")
+		buf.WriteString("<ETag>" + entry.ETag + "</ETag>")
+		buf.WriteString("<LastModified>" + entry.LastModified.Format(time.RFC3339) + "</LastModified>")
+		buf.WriteString("</Contents>")
+	}
+	buf.WriteString("</ListBucketResult>")
+	return buf.String()
+}
+
+// RenderAzureListing renders entries as the Azure Blob Storage
+// EnumerationResults body.
+func RenderAzureListing(entries []Entry) string {
+	var buf strings.Builder
+	buf.WriteString("<EnumerationResults><Blobs>")
+	for _, entry := range entries {
+		buf.WriteString("<Blob>")
+		buf.WriteString("<Name>" + entry.Key + "</Name>")
+		buf.WriteString("<Properties>")
+		buf.WriteString("<Content-Length>" + strconv.FormatInt(entry.Size, 10) + "</Content-Length>")
+		buf.WriteString("<Etag>" + entry.ETag + "</Etag>")
+		buf.WriteString("<Last-Modified>" + entry.LastModified.Format(time.RFC3339) + "</Last-Modified>")
+		buf.WriteString("</Properties>")
+		buf.WriteString("</Blob>")
+	}
+	buf.WriteString("</Blobs></EnumerationResults>")
+	return buf.String()
+}
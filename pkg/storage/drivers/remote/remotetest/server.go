@@ -0,0 +1,177 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package remotetest provides a minimal in-memory stand-in for the
+// path-style PUT/GET/HEAD/DELETE/LIST HTTP surface remote.Client talks to,
+// so the S3, Azure and GCS drivers can run storagetest.Run against it
+// instead of a real cloud endpoint. Each provider's driver_test.go picks
+// the Render function (RenderS3Listing or RenderAzureListing) matching its
+// wire-specific listing format.
+package remotetest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one stored object as seen by a Render function.
+type Entry struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// Render encodes entries matching a list request into a provider-specific
+// listing response body.
+type Render func(entries []Entry) string
+
+// CheckAuth validates an incoming request's authentication, the same way
+// the real provider would, returning a non-nil error to reject it with
+// 403 Forbidden.
+type CheckAuth func(req *http.Request) error
+
+// NewServer returns an http.Handler backed by an in-memory bucket->key->body
+// map, implementing just enough of the path-style REST surface
+// remote.Client uses for PUT/GET/HEAD/DELETE/LIST. It performs no
+// authentication; use NewAuthenticatedServer to also exercise a driver's
+// real Signer.
+func NewServer(render Render) http.Handler {
+	return NewAuthenticatedServer(render, nil)
+}
+
+// NewAuthenticatedServer is NewServer plus a checkAuth hook run against
+// every request before it is served, so a driver_test.go can wire its
+// package's real Signer through this server and confirm the provider
+// would actually accept the signature it produces.
+func NewAuthenticatedServer(render Render, checkAuth CheckAuth) http.Handler {
+	srv := &fakeServer{render: render, checkAuth: checkAuth, objects: make(map[string][]byte)}
+	return http.HandlerFunc(srv.serveHTTP)
+}
+
+type fakeServer struct {
+	mu        sync.Mutex
+	render    Render
+	checkAuth CheckAuth
+	objects   map[string][]byte // "bucket/key" -> body
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.checkAuth != nil {
+		if err := s.checkAuth(req); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	bucket, key, isBucketRoot := splitPath(req.URL.Path)
+
+	switch {
+	case req.Method == "GET" && isBucketRoot:
+		s.list(w, bucket, req.URL.Query().Get("prefix"))
+	case req.Method == "PUT" && isBucketRoot:
+		w.WriteHeader(http.StatusOK)
+	case req.Method == "PUT":
+		s.put(w, req, bucket, key)
+	case req.Method == "GET":
+		s.get(w, bucket, key, false)
+	case req.Method == "HEAD":
+		s.get(w, bucket, key, true)
+	case req.Method == "DELETE":
+		s.delete(w, bucket, key)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeServer) put(w http.ResponseWriter, req *http.Request, bucket, key string) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.mu.Lock()
+	s.objects[bucket+"/"+key] = body
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *fakeServer) get(w http.ResponseWriter, bucket, key string, headOnly bool) {
+	s.mu.Lock()
+	body, ok := s.objects[bucket+"/"+key]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("ETag", etagOf(body))
+	w.Header().Set("Last-Modified", time.Now().UTC().Format(time.RFC1123))
+	w.WriteHeader(http.StatusOK)
+	if !headOnly {
+		w.Write(body)
+	}
+}
+
+func (s *fakeServer) delete(w http.ResponseWriter, bucket, key string) {
+	s.mu.Lock()
+	delete(s.objects, bucket+"/"+key)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *fakeServer) list(w http.ResponseWriter, bucket, prefix string) {
+	s.mu.Lock()
+	var entries []Entry
+	for fullKey, body := range s.objects {
+		b, key, ok := strings.Cut(fullKey, "/")
+		if !ok || b != bucket || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		entries = append(entries, Entry{Key: key, Size: int64(len(body)), ETag: etagOf(body), LastModified: time.Now().UTC()})
+	}
+	s.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, s.render(entries))
+}
+
+func etagOf(body []byte) string {
+	sum := md5.Sum(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitPath splits a path-style request URL of the form /bucket or
+// /bucket/key into its bucket and key, reporting isBucketRoot when there is
+// no key component (a request against the bucket itself: StoreBucket,
+// DeleteBucket or List).
+func splitPath(path string) (bucket, key string, isBucketRoot bool) {
+	trimmed := strings.TrimPrefix(path, "/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	bucket = parts[0]
+	if len(parts) == 1 {
+		return bucket, "", true
+	}
+	return bucket, parts[1], false
+}
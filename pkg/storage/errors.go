@@ -0,0 +1,100 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import "fmt"
+
+// ObjectNotFound is returned by GetObjectMetadata and CopyObjectToWriter
+// when the requested object does not exist.
+type ObjectNotFound struct {
+	Bucket string
+	Object string
+}
+
+func (e ObjectNotFound) Error() string {
+	return "Object not found: " + e.Bucket + "#" + e.Object
+}
+
+// BucketNotFound is returned when an operation targets a bucket that does
+// not exist.
+type BucketNotFound struct {
+	Bucket string
+}
+
+func (e BucketNotFound) Error() string {
+	return "Bucket not found: " + e.Bucket
+}
+
+// PolicyNotFound is returned by GetBucketPolicy when no policy has been
+// set on the bucket.
+type PolicyNotFound struct {
+	Bucket string
+}
+
+func (e PolicyNotFound) Error() string {
+	return "Bucket policy not found: " + e.Bucket
+}
+
+// InvalidRange is returned by CopyObjectRangeToWriter when the requested
+// byte range falls outside the object's size.
+type InvalidRange struct {
+	Bucket string
+	Object string
+	Size   int
+}
+
+func (e InvalidRange) Error() string {
+	return "Requested range not satisfiable: " + e.Bucket + "#" + e.Object
+}
+
+// UploadNotFound is returned when a multipart upload operation references
+// an uploadID that is unknown or already completed/aborted.
+type UploadNotFound struct {
+	Bucket   string
+	Object   string
+	UploadID string
+}
+
+func (e UploadNotFound) Error() string {
+	return "Upload not found: " + e.Bucket + "#" + e.Object + "#" + e.UploadID
+}
+
+// InvalidPartOrder is returned by CompleteMultipartUpload when the parts
+// list is not strictly ascending by PartNumber (out of order or
+// duplicated), the same condition S3 rejects with InvalidPartOrder.
+type InvalidPartOrder struct {
+	Bucket   string
+	Object   string
+	UploadID string
+}
+
+func (e InvalidPartOrder) Error() string {
+	return "Parts not in ascending order: " + e.Bucket + "#" + e.Object + "#" + e.UploadID
+}
+
+// InvalidPart is returned by CompleteMultipartUpload when a part's
+// client-supplied ETag does not match the MD5 actually stored for it.
+type InvalidPart struct {
+	Bucket     string
+	Object     string
+	UploadID   string
+	PartNumber int
+}
+
+func (e InvalidPart) Error() string {
+	return fmt.Sprintf("Part ETag does not match stored content: %s#%s#%s part %d", e.Bucket, e.Object, e.UploadID, e.PartNumber)
+}
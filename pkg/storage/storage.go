@@ -0,0 +1,119 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storage defines the Storage interface that backs the S3-compatible
+// HTTP API in pkg/webapi/minioapi, along with the metadata types it exchanges.
+package storage
+
+import (
+	"io"
+	"time"
+
+	"github.com/minio-io/minio/pkg/policy"
+)
+
+// BucketMetadata describes a bucket as returned by ListBuckets.
+type BucketMetadata struct {
+	Name    string
+	Created time.Time
+}
+
+// ObjectMetadata describes an object as returned by GetObjectMetadata and
+// ListObjects.
+type ObjectMetadata struct {
+	Key     string
+	Created time.Time
+	ETag    string
+	Size    int
+}
+
+// CompletedPart identifies one part of a multipart upload, as supplied by
+// the client in a CompleteMultipartUpload request.
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// ValidatePartOrder reports InvalidPartOrder if parts is not strictly
+// ascending by PartNumber, the same ordering S3 requires in a
+// CompleteMultipartUpload request body; a driver's CompleteMultipartUpload
+// should call this before concatenating parts, since out-of-order or
+// duplicated parts would otherwise silently produce a corrupt object.
+func ValidatePartOrder(bucket, object, uploadID string, parts []CompletedPart) error {
+	for i := 1; i < len(parts); i++ {
+		if parts[i].PartNumber <= parts[i-1].PartNumber {
+			return InvalidPartOrder{Bucket: bucket, Object: object, UploadID: uploadID}
+		}
+	}
+	return nil
+}
+
+// ObjectPart describes a single part already uploaded in an in-progress
+// multipart upload, as returned by ListParts.
+type ObjectPart struct {
+	PartNumber   int
+	ETag         string
+	Size         int
+	LastModified time.Time
+}
+
+// ListObjectsResult is the result of a (possibly paginated, possibly
+// delimited) ListObjects call.
+type ListObjectsResult struct {
+	Objects        []ObjectMetadata
+	CommonPrefixes []string
+	NextMarker     string
+	IsTruncated    bool
+}
+
+// Storage is the interface every backend (filesystem, remote object store)
+// implements to serve the S3-compatible API.
+type Storage interface {
+	ListBuckets(prefix string) []BucketMetadata
+	StoreBucket(bucket string) error
+
+	GetBucketPolicy(bucket string) (policy.Policy, error)
+	SetBucketPolicy(bucket string, p policy.Policy) error
+	DeleteBucketPolicy(bucket string) error
+
+	// ListObjects lists objects in bucket whose key starts with prefix,
+	// resuming after marker. When delimiter is non-empty, keys
+	// containing it past the prefix are rolled up into CommonPrefixes
+	// instead of being returned individually, emulating directory-style
+	// listing.
+	ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsResult, error)
+	GetObjectMetadata(bucket, object string) (ObjectMetadata, error)
+	StoreObject(bucket, object string, data io.Reader) error
+	DeleteObject(bucket, object string) error
+	DeleteBucket(bucket string) error
+	CopyObjectToWriter(w io.Writer, bucket, object string) (int64, error)
+	// CopyObjectRangeToWriter writes length bytes starting at offset into
+	// w. It returns ObjectNotFound for an unknown object. The fs driver
+	// returns an InvalidRange error when offset/length fall outside the
+	// object; the s3, azure and gcs drivers return the remote store's own
+	// error for such a range, not InvalidRange.
+	CopyObjectRangeToWriter(w io.Writer, bucket, object string, offset, length int64) (int64, error)
+
+	InitiateMultipartUpload(bucket, object string) (uploadID string, err error)
+	PutObjectPart(bucket, object, uploadID string, partNumber int, body io.Reader) (etag string, err error)
+	// CompleteMultipartUpload assembles parts, in the order given, into the
+	// final object. Per S3 semantics the resulting ETag is not a plain MD5
+	// of the object body; it is the MD5 of the concatenated raw MD5 bytes
+	// of every part, hex-encoded with a "-<number of parts>" suffix.
+	CompleteMultipartUpload(bucket, object, uploadID string, parts []CompletedPart) (ObjectMetadata, error)
+	AbortMultipartUpload(bucket, object, uploadID string) error
+	ListParts(bucket, object, uploadID string) ([]ObjectPart, error)
+}
@@ -0,0 +1,130 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storagetest is a conformance suite every storage.Storage backend
+// runs against, so the filesystem, S3, Azure and GCS drivers are all held
+// to the same observable behavior.
+package storagetest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/minio-io/minio/pkg/storage"
+)
+
+// Run exercises newStorage() (freshly constructed per call) against the
+// put/get/head/metadata round-trip, empty-object semantics and
+// prefix/delimiter listing every backend is expected to support.
+func Run(t *testing.T, newStorage func() storage.Storage) {
+	t.Run("PutGetRoundTrip", func(t *testing.T) { testPutGetRoundTrip(t, newStorage()) })
+	t.Run("EmptyObject", func(t *testing.T) { testEmptyObject(t, newStorage()) })
+	t.Run("PrefixListing", func(t *testing.T) { testPrefixListing(t, newStorage()) })
+}
+
+func testPutGetRoundTrip(t *testing.T, s storage.Storage) {
+	const bucket, object = "conformance-bucket", "hello.txt"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+
+	body := []byte("hello, minio")
+	if err := s.StoreObject(bucket, object, bytes.NewReader(body)); err != nil {
+		t.Fatalf("StoreObject: %v", err)
+	}
+
+	var out bytes.Buffer
+	n, err := s.CopyObjectToWriter(&out, bucket, object)
+	if err != nil {
+		t.Fatalf("CopyObjectToWriter: %v", err)
+	}
+	if n != int64(len(body)) || out.String() != string(body) {
+		t.Fatalf("CopyObjectToWriter = %q (%d bytes), want %q", out.String(), n, body)
+	}
+
+	metadata, err := s.GetObjectMetadata(bucket, object)
+	if err != nil {
+		t.Fatalf("GetObjectMetadata: %v", err)
+	}
+	if metadata.Key != object || metadata.Size != len(body) {
+		t.Fatalf("GetObjectMetadata = %+v, want Key=%q Size=%d", metadata, object, len(body))
+	}
+}
+
+func testEmptyObject(t *testing.T, s storage.Storage) {
+	const bucket, object = "conformance-bucket", "empty.txt"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+	if err := s.StoreObject(bucket, object, bytes.NewReader(nil)); err != nil {
+		t.Fatalf("StoreObject: %v", err)
+	}
+
+	metadata, err := s.GetObjectMetadata(bucket, object)
+	if err != nil {
+		t.Fatalf("GetObjectMetadata: %v", err)
+	}
+	if metadata.Size != 0 {
+		t.Fatalf("GetObjectMetadata.Size = %d, want 0", metadata.Size)
+	}
+
+	var out bytes.Buffer
+	n, err := s.CopyObjectToWriter(&out, bucket, object)
+	if err != nil {
+		t.Fatalf("CopyObjectToWriter: %v", err)
+	}
+	if n != 0 || out.Len() != 0 {
+		t.Fatalf("CopyObjectToWriter copied %d bytes, want 0", n)
+	}
+}
+
+func testPrefixListing(t *testing.T, s storage.Storage) {
+	const bucket = "conformance-bucket"
+	if err := s.StoreBucket(bucket); err != nil {
+		t.Fatalf("StoreBucket: %v", err)
+	}
+
+	keys := []string{"a/1.txt", "a/2.txt", "b/1.txt", "c.txt"}
+	for _, key := range keys {
+		if err := s.StoreObject(bucket, key, bytes.NewReader([]byte(key))); err != nil {
+			t.Fatalf("StoreObject(%q): %v", key, err)
+		}
+	}
+
+	result, err := s.ListObjects(bucket, "a/", "", "", 1000)
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	if len(result.Objects) != 2 {
+		t.Fatalf("ListObjects(prefix=a/) returned %d objects, want 2: %+v", len(result.Objects), result.Objects)
+	}
+	for _, object := range result.Objects {
+		if object.Key != "a/1.txt" && object.Key != "a/2.txt" {
+			t.Fatalf("ListObjects(prefix=a/) returned unexpected key %q", object.Key)
+		}
+	}
+
+	result, err = s.ListObjects(bucket, "", "", "/", 1000)
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Key != "c.txt" {
+		t.Fatalf("ListObjects(delimiter=/) Objects = %+v, want just c.txt", result.Objects)
+	}
+	if len(result.CommonPrefixes) != 2 {
+		t.Fatalf("ListObjects(delimiter=/) CommonPrefixes = %v, want [a/ b/]", result.CommonPrefixes)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+// maxBulkDeleteObjects is the largest number of keys accepted in a single
+// bulk-delete request, matching the S3 limit.
+const maxBulkDeleteObjects = 1000
+
+// deleteRequest is the body of POST /{bucket}?delete.
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+// DeleteResult is the response to a bulk-delete request.
+type DeleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Deleted []DeletedObject
+	Errors  []DeleteError `xml:"Error"`
+}
+
+// DeletedObject records one key that was successfully removed.
+type DeletedObject struct {
+	Key string
+}
+
+// DeleteError records one key that could not be removed.
+type DeleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+func (server *minioApi) deleteObjectHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	err := server.storage.DeleteObject(bucket, object)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.ObjectNotFound:
+		log.Println(err)
+		w.WriteHeader(http.StatusNoContent) // S3 DeleteObject is idempotent
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) deleteBucketHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.storage.DeleteBucket(bucket)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.BucketNotFound:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchBucket", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) bulkDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	var request deleteRequest
+	if err := xml.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeErrorResponse(w, req, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+	if len(request.Objects) > maxBulkDeleteObjects {
+		writeErrorResponse(w, req, http.StatusBadRequest, "MalformedXML", "The request contains more than 1000 keys")
+		return
+	}
+
+	var result DeleteResult
+	for _, object := range request.Objects {
+		err := server.storage.DeleteObject(bucket, object.Key)
+		switch err.(type) {
+		case nil, mstorage.ObjectNotFound:
+			if !request.Quiet {
+				result.Deleted = append(result.Deleted, DeletedObject{Key: object.Key})
+			}
+		default:
+			log.Println(err)
+			result.Errors = append(result.Errors, DeleteError{
+				Key:     object.Key,
+				Code:    "InternalError",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
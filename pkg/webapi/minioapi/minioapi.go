@@ -22,10 +22,13 @@ import (
 	"encoding/xml"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/minio-io/minio/pkg/auth"
 	mstorage "github.com/minio-io/minio/pkg/storage"
 )
 
@@ -38,6 +41,11 @@ const (
 
 const (
 	dateFormat = "2006-01-02T15:04:05.000Z"
+
+	// signingRegion is the AWS region used to verify SigV4 signatures.
+	// Minio does not yet model multiple regions, so a single static
+	// region is used for signing-key derivation.
+	signingRegion = "us-east-1"
 )
 
 type minioApi struct {
@@ -49,28 +57,102 @@ type encoder interface {
 	Encode(v interface{}) error
 }
 
-func HttpHandler(storage mstorage.Storage) http.Handler {
+// HttpHandler wires up the S3-compatible API routes on top of storage,
+// authenticating every request with an AWS Signature V4 verifier backed by
+// credentials.
+func HttpHandler(storage mstorage.Storage, credentials *auth.CredentialStore) http.Handler {
 	mux := mux.NewRouter()
 	api := minioApi{
 		storage: storage,
 	}
 
+	verifier := auth.NewSignatureV4Verifier(credentials, signingRegion)
+	mux.Use(auth.Middleware(verifier, writeSignatureError))
+	mux.Use(api.authorizationMiddleware)
+
 	mux.HandleFunc("/", api.listBucketsHandler).Methods("GET")
+
+	// Bucket-policy routes are registered ahead of the plain bucket
+	// routes below for the same reason as the multipart routes further
+	// down: an unconstrained route registered first would shadow them.
+	mux.HandleFunc("/{bucket}", api.getBucketPolicyHandler).Methods("GET").Queries("policy", "")
+	mux.HandleFunc("/{bucket}", api.putBucketPolicyHandler).Methods("PUT").Queries("policy", "")
+	mux.HandleFunc("/{bucket}", api.deleteBucketPolicyHandler).Methods("DELETE").Queries("policy", "")
+
 	mux.HandleFunc("/{bucket}", api.listObjectsHandler).Methods("GET")
 	mux.HandleFunc("/{bucket}", api.putBucketHandler).Methods("PUT")
+	mux.HandleFunc("/{bucket}", api.bulkDeleteHandler).Methods("POST").Queries("delete", "")
+	mux.HandleFunc("/{bucket}", api.deleteBucketHandler).Methods("DELETE")
 	mux.HandleFunc("/{bucket}/", api.listObjectsHandler).Methods("GET")
+
+	// Multipart-upload routes are registered ahead of the plain object
+	// routes below: gorilla/mux matches in registration order and the
+	// plain routes carry no query-string constraint, so they would
+	// otherwise shadow these.
+	mux.HandleFunc("/{bucket}/{object:.*}", api.listPartsHandler).Methods("GET").Queries("uploadId", "{uploadId}")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.initiateMultipartUploadHandler).Methods("POST").Queries("uploads", "")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectPartHandler).Methods("PUT").Queries("partNumber", "{partNumber}", "uploadId", "{uploadId}")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.completeMultipartUploadHandler).Methods("POST").Queries("uploadId", "{uploadId}")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.abortMultipartUploadHandler).Methods("DELETE").Queries("uploadId", "{uploadId}")
+
 	mux.HandleFunc("/{bucket}/{object:.*}", api.getObjectHandler).Methods("GET")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.headObjectHandler).Methods("HEAD")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectHandler).Methods("PUT")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.deleteObjectHandler).Methods("DELETE")
+
 	return mux
 }
 
+// Error is the S3-compatible XML error document written on request
+// failures.
+type Error struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string
+	RequestId string
+}
+
+func writeErrorResponse(w http.ResponseWriter, req *http.Request, statusCode int, code, message string) {
+	response := Error{
+		Code:      code,
+		Message:   message,
+		Resource:  req.URL.Path,
+		RequestId: "minio",
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	xml.NewEncoder(w).Encode(response)
+}
+
+// writeSignatureError maps an auth verification failure to the matching
+// S3-compatible error response.
+func writeSignatureError(w http.ResponseWriter, req *http.Request, err error) {
+	switch err {
+	case auth.ErrSignatureDoesNotMatch:
+		writeErrorResponse(w, req, http.StatusForbidden, "SignatureDoesNotMatch",
+			"The request signature we calculated does not match the signature you provided.")
+	case auth.ErrRequestExpired:
+		writeErrorResponse(w, req, http.StatusForbidden, "RequestTimeTooSkewed",
+			"The difference between the request time and the current time is too large.")
+	case auth.ErrUnknownAccessKey:
+		writeErrorResponse(w, req, http.StatusForbidden, "InvalidAccessKeyId",
+			"The access key ID you provided does not exist in our records.")
+	case auth.ErrContentSHA256Mismatch:
+		writeErrorResponse(w, req, http.StatusBadRequest, "XAmzContentSHA256Mismatch",
+			"The X-Amz-Content-Sha256 you specified did not match what we received.")
+	default:
+		writeErrorResponse(w, req, http.StatusForbidden, "AccessDenied", err.Error())
+	}
+}
+
 func writeObjectHeaders(w http.ResponseWriter, metadata mstorage.ObjectMetadata) {
 	lastModified := metadata.Created.Format(time.RFC1123)
 	w.Header().Set("ETag", metadata.ETag)
 	w.Header().Set("Last-Modified", lastModified)
 	w.Header().Set("Content-Length", strconv.Itoa(metadata.Size))
 	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Accept-Ranges", "bytes")
 }
 
 func (server *minioApi) getObjectHandler(w http.ResponseWriter, req *http.Request) {
@@ -83,8 +165,32 @@ func (server *minioApi) getObjectHandler(w http.ResponseWriter, req *http.Reques
 	case nil: // success
 		{
 			log.Println("Found: " + bucket + "#" + object)
+			if status := checkPreconditions(req, metadata); status != 0 {
+				w.WriteHeader(status)
+				return
+			}
+
+			rangeHeader := req.Header.Get("Range")
+			if rangeHeader == "" {
+				writeObjectHeaders(w, metadata)
+				if _, err := server.storage.CopyObjectToWriter(w, bucket, object); err != nil {
+					log.Println(err)
+				}
+				return
+			}
+
+			offset, length, err := parseRangeHeader(rangeHeader, int64(metadata.Size))
+			if err != nil {
+				w.Header().Set("Content-Range", "bytes */"+strconv.Itoa(metadata.Size))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+
 			writeObjectHeaders(w, metadata)
-			if _, err := server.storage.CopyObjectToWriter(w, bucket, object); err != nil {
+			w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+			w.Header().Set("Content-Range", formatContentRange(offset, length, int64(metadata.Size)))
+			w.WriteHeader(http.StatusPartialContent)
+			if _, err := server.storage.CopyObjectRangeToWriter(w, bucket, object, offset, length); err != nil {
 				log.Println(err)
 			}
 		}
@@ -149,21 +255,40 @@ func (server *minioApi) listBucketsHandler(w http.ResponseWriter, req *http.Requ
 }
 
 func (server *minioApi) listObjectsHandler(w http.ResponseWriter, req *http.Request) {
-	vars := mux.Vars(req)
+	bucket := mux.Vars(req)["bucket"]
+	query := req.URL.Query()
+
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	encodingType := query.Get("encoding-type")
+	maxKeys := MAX_OBJECT_LIST
+	if raw := query.Get("max-keys"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			maxKeys = parsed
+		}
+	}
 
-	//delimiter, ok := vars["delimiter"]
-	//encodingType, ok := vars["encoding-type"]
-	//marker, ok := vars["marker"]
-	//maxKeys, ok := vars["max-keys"]
-	bucket := vars["bucket"]
-	//bucket, ok := vars["bucket"]
-	//if ok == false {
-	//	w.WriteHeader(http.StatusBadRequest)
-	//	return
-	//}
-	prefix, ok := vars["prefix"]
-	if ok == false {
-		prefix = ""
+	v2 := query.Get("list-type") == "2"
+	marker := query.Get("marker")
+	if v2 {
+		marker = query.Get("start-after")
+		if token := query.Get("continuation-token"); token != "" {
+			marker = token
+		}
+	}
+
+	result, err := server.storage.ListObjects(bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	var response interface{}
+	if v2 {
+		response = generateObjectsListV2Result(bucket, prefix, delimiter, encodingType, maxKeys, result)
+	} else {
+		response = generateObjectsListResult(bucket, encodingType, maxKeys, result)
 	}
 
 	contentType := xmlType
@@ -173,9 +298,6 @@ func (server *minioApi) listObjectsHandler(w http.ResponseWriter, req *http.Requ
 		}
 	}
 
-	objects := server.storage.ListObjects(bucket, prefix, 1000)
-	response := generateObjectsListResult(bucket, objects)
-
 	var bytesBuffer bytes.Buffer
 	var encoder encoder
 	if contentType == xmlType {
@@ -236,30 +358,111 @@ func generateBucketsListResult(buckets []mstorage.BucketMetadata) (data BucketLi
 	return
 }
 
-func generateObjectsListResult(bucket string, objects []mstorage.ObjectMetadata) (data ObjectListResponse) {
-	var contents []*Item
+// CommonPrefix is the rolled-up representation of keys sharing a delimiter
+// past the requested prefix, as returned alongside Contents when a
+// delimiter is given.
+type CommonPrefix struct {
+	Prefix string
+}
+
+// urlEncode percent-encodes s the way S3's encoding-type=url asks for, so
+// a key or prefix containing characters illegal in XML 1.0 (or just
+// awkward to read, like raw control characters) can still be carried in
+// an XML response body. Unlike url.QueryEscape on its own, space encodes
+// to %20, not +.
+func urlEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
 
+func objectListContents(objects []mstorage.ObjectMetadata, encodingType string) []*Item {
 	owner := Owner{
 		ID:          "minio",
 		DisplayName: "minio",
 	}
 
+	var contents []*Item
 	for _, object := range objects {
-		content := &Item{
-			Key:          object.Key,
+		key := object.Key
+		if encodingType == "url" {
+			key = urlEncode(key)
+		}
+		contents = append(contents, &Item{
+			Key:          key,
 			LastModified: object.Created.Format(dateFormat),
 			ETag:         object.ETag,
 			Size:         object.Size,
 			StorageClass: "STANDARD",
 			Owner:        owner,
+		})
+	}
+	return contents
+}
+
+func objectListCommonPrefixes(prefixes []string, encodingType string) []*CommonPrefix {
+	var commonPrefixes []*CommonPrefix
+	for _, prefix := range prefixes {
+		if encodingType == "url" {
+			prefix = urlEncode(prefix)
 		}
-		contents = append(contents, content)
+		commonPrefixes = append(commonPrefixes, &CommonPrefix{Prefix: prefix})
+	}
+	return commonPrefixes
+}
+
+func generateObjectsListResult(bucket, encodingType string, maxKeys int, result mstorage.ListObjectsResult) (data ObjectListResponse) {
+	nextMarker := result.NextMarker
+	if encodingType == "url" {
+		nextMarker = urlEncode(nextMarker)
 	}
 	data = ObjectListResponse{
-		Name:        bucket,
-		Contents:    contents,
-		MaxKeys:     MAX_OBJECT_LIST,
-		IsTruncated: false,
+		Name:           bucket,
+		EncodingType:   encodingType,
+		Contents:       objectListContents(result.Objects, encodingType),
+		CommonPrefixes: objectListCommonPrefixes(result.CommonPrefixes, encodingType),
+		MaxKeys:        maxKeys,
+		IsTruncated:    result.IsTruncated,
+		NextMarker:     nextMarker,
+	}
+	return
+}
+
+// ObjectListV2Response mirrors ObjectListResponse for the list-type=2 S3
+// API variant, which reports a key count and a continuation token instead
+// of a bare marker.
+type ObjectListV2Response struct {
+	Name                  string
+	Prefix                string
+	Delimiter             string
+	EncodingType          string `xml:",omitempty"`
+	MaxKeys               int
+	KeyCount              int
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+	Contents              []*Item
+	CommonPrefixes        []*CommonPrefix
+}
+
+func generateObjectsListV2Result(bucket, prefix, delimiter, encodingType string, maxKeys int, result mstorage.ListObjectsResult) (data ObjectListV2Response) {
+	if encodingType == "url" {
+		prefix = urlEncode(prefix)
+		delimiter = urlEncode(delimiter)
+	}
+	data = ObjectListV2Response{
+		Name:           bucket,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+		EncodingType:   encodingType,
+		Contents:       objectListContents(result.Objects, encodingType),
+		CommonPrefixes: objectListCommonPrefixes(result.CommonPrefixes, encodingType),
+		MaxKeys:        maxKeys,
+		KeyCount:       len(result.Objects) + len(result.CommonPrefixes),
+		IsTruncated:    result.IsTruncated,
+	}
+	if result.IsTruncated {
+		data.NextContinuationToken = result.NextMarker
+		if encodingType == "url" {
+			data.NextContinuationToken = urlEncode(data.NextContinuationToken)
+		}
 	}
 	return
 }
\ No newline at end of file
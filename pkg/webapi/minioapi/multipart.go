@@ -0,0 +1,208 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+// InitiateMultipartUploadResult is the response to
+// POST /{bucket}/{object}?uploads.
+type InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string
+	Key      string
+	UploadId string
+}
+
+// completeMultipartUploadRequest is the body of
+// POST /{bucket}/{object}?uploadId=X.
+type completeMultipartUploadRequest struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+// CompleteMultipartUploadResult is the response to a successful
+// CompleteMultipartUpload request.
+type CompleteMultipartUploadResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Bucket  string
+	Key     string
+	ETag    string
+}
+
+// ListPartsResult is the response to GET /{bucket}/{object}?uploadId=X.
+type ListPartsResult struct {
+	XMLName  xml.Name `xml:"ListPartsResult"`
+	Bucket   string
+	Key      string
+	UploadId string
+	Part     []Part
+}
+
+// Part describes one already-uploaded part within ListPartsResult.
+type Part struct {
+	PartNumber   int
+	ETag         string
+	Size         int
+	LastModified string
+}
+
+func (server *minioApi) initiateMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	uploadID, err := server.storage.InitiateMultipartUpload(bucket, object)
+	if err != nil {
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+
+	response := InitiateMultipartUploadResult{
+		Bucket:   bucket,
+		Key:      object,
+		UploadId: uploadID,
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(response)
+}
+
+func (server *minioApi) putObjectPartHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	partNumber, err := strconv.Atoi(vars["partNumber"])
+	if err != nil {
+		writeErrorResponse(w, req, http.StatusBadRequest, "InvalidArgument", "partNumber must be an integer")
+		return
+	}
+
+	etag, err := server.storage.PutObjectPart(bucket, object, uploadID, partNumber, req.Body)
+	switch err.(type) {
+	case nil:
+		w.Header().Set("ETag", etag)
+	case mstorage.UploadNotFound:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchUpload", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) completeMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	var request completeMultipartUploadRequest
+	if err := xml.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeErrorResponse(w, req, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	parts := make([]mstorage.CompletedPart, len(request.Parts))
+	for i, part := range request.Parts {
+		parts[i] = mstorage.CompletedPart{PartNumber: part.PartNumber, ETag: part.ETag}
+	}
+
+	metadata, err := server.storage.CompleteMultipartUpload(bucket, object, uploadID, parts)
+	switch err.(type) {
+	case nil:
+		response := CompleteMultipartUploadResult{
+			Bucket: bucket,
+			Key:    object,
+			ETag:   metadata.ETag,
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(response)
+	case mstorage.UploadNotFound:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchUpload", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) abortMultipartUploadHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	err := server.storage.AbortMultipartUpload(bucket, object, uploadID)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.UploadNotFound:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchUpload", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) listPartsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+	uploadID := vars["uploadId"]
+
+	objectParts, err := server.storage.ListParts(bucket, object, uploadID)
+	switch err.(type) {
+	case nil:
+		var parts []Part
+		for _, part := range objectParts {
+			parts = append(parts, Part{
+				PartNumber:   part.PartNumber,
+				ETag:         part.ETag,
+				Size:         part.Size,
+				LastModified: part.LastModified.Format(dateFormat),
+			})
+		}
+		response := ListPartsResult{
+			Bucket:   bucket,
+			Key:      object,
+			UploadId: uploadID,
+			Part:     parts,
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(response)
+	case mstorage.UploadNotFound:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchUpload", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
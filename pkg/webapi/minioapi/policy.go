@@ -0,0 +1,154 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio-io/minio/pkg/auth"
+	"github.com/minio-io/minio/pkg/policy"
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+func (server *minioApi) getBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	bucket := mux.Vars(req)["bucket"]
+
+	p, err := server.storage.GetBucketPolicy(bucket)
+	switch err.(type) {
+	case nil:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(p)
+	case mstorage.PolicyNotFound:
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchBucketPolicy", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+func (server *minioApi) putBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	bucket := mux.Vars(req)["bucket"]
+
+	var p policy.Policy
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		writeErrorResponse(w, req, http.StatusBadRequest, "MalformedPolicy", err.Error())
+		return
+	}
+
+	if err := server.storage.SetBucketPolicy(bucket, p); err != nil {
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (server *minioApi) deleteBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	bucket := mux.Vars(req)["bucket"]
+
+	err := server.storage.DeleteBucketPolicy(bucket)
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case mstorage.PolicyNotFound:
+		writeErrorResponse(w, req, http.StatusNotFound, "NoSuchBucketPolicy", err.Error())
+	default:
+		log.Println(err)
+		writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+	}
+}
+
+// authorizationMiddleware enforces bucket policies on the operations S3
+// policies commonly govern: GetObject, PutObject, ListBucket, and
+// CompleteMultipartUpload (evaluated as s3:PutObject, since it is the
+// request that actually commits a multipart upload's data). Other routes
+// (InitiateMultipartUpload, PutObjectPart, AbortMultipartUpload,
+// ListParts, bulk-delete, the policy endpoint itself, ...) are left to
+// signature verification alone. Requests to a bucket with no
+// policy set fall back to owner-only access: since this request already
+// passed signature verification by the time this middleware runs, it is
+// allowed. The principal evaluated against the policy is the access key ID
+// that auth.Middleware verified the request under, so Statement.Principal.AWS
+// entries naming a specific access key (not just "*") are honored.
+func (server *minioApi) authorizationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		action, resource, ok := policyAction(req)
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		bucket := mux.Vars(req)["bucket"]
+		p, err := server.storage.GetBucketPolicy(bucket)
+		switch err.(type) {
+		case nil:
+			if !policy.Allowed(p, auth.AccessKeyID(req), action, resource) {
+				writeErrorResponse(w, req, http.StatusForbidden, "AccessDenied",
+					"User is not authorized to perform this action")
+				return
+			}
+		case mstorage.PolicyNotFound:
+			// No policy configured: fall back to owner-only access.
+		default:
+			log.Println(err)
+			writeErrorResponse(w, req, http.StatusInternalServerError, "InternalError", err.Error())
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// policyAction maps a request to the S3 action and ARN resource a bucket
+// policy would evaluate it against. ok is false for requests outside the
+// policy-governed operations (GetObject, PutObject, ListBucket, and
+// CompleteMultipartUpload as s3:PutObject — a policy that denies
+// s3:PutObject must still block a write that arrives as a completed
+// multipart upload rather than a single PUT).
+func policyAction(req *http.Request) (action, resource string, ok bool) {
+	query := req.URL.Query()
+	if query.Has("policy") || query.Has("delete") {
+		return "", "", false
+	}
+
+	vars := mux.Vars(req)
+	bucket, hasBucket := vars["bucket"]
+	if !hasBucket {
+		return "", "", false
+	}
+	object, hasObject := vars["object"]
+
+	isCompleteMultipartUpload := req.Method == "POST" && query.Has("uploadId") && !query.Has("partNumber")
+	if query.Has("uploads") || query.Has("uploadId") && !isCompleteMultipartUpload {
+		return "", "", false
+	}
+
+	switch {
+	case hasObject && isCompleteMultipartUpload:
+		return "s3:PutObject", "arn:aws:s3:::" + bucket + "/" + object, true
+	case hasObject && req.Method == "GET":
+		return "s3:GetObject", "arn:aws:s3:::" + bucket + "/" + object, true
+	case hasObject && req.Method == "PUT":
+		return "s3:PutObject", "arn:aws:s3:::" + bucket + "/" + object, true
+	case !hasObject && req.Method == "GET":
+		return "s3:ListBucket", "arn:aws:s3:::" + bucket, true
+	default:
+		return "", "", false
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Mini Object Storage, (C) 2014 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minioapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	mstorage "github.com/minio-io/minio/pkg/storage"
+)
+
+var errInvalidRange = errors.New("minioapi: invalid range")
+
+// parseRangeHeader parses a single RFC 7233 "bytes=" range
+// (start-end, start- or -suffix) and returns the offset and length it
+// covers within an object of the given size.
+func parseRangeHeader(header string, size int64) (offset, length int64, err error) {
+	if !strings.HasPrefix(header, "bytes=") {
+		return 0, 0, errInvalidRange
+	}
+	spec := strings.TrimPrefix(header, "bytes=")
+	if strings.Contains(spec, ",") {
+		// Multiple ranges are not supported; reject rather than guess.
+		return 0, 0, errInvalidRange
+	}
+
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, errInvalidRange
+	}
+
+	switch {
+	case parts[0] == "" && parts[1] != "":
+		// bytes=-suffix: last N bytes of the object.
+		suffix, convErr := strconv.ParseInt(parts[1], 10, 64)
+		if convErr != nil || suffix <= 0 || size == 0 {
+			return 0, 0, errInvalidRange
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, nil
+	case parts[1] == "":
+		// bytes=start-: from start to the end of the object.
+		start, convErr := strconv.ParseInt(parts[0], 10, 64)
+		if convErr != nil || start < 0 || start >= size {
+			return 0, 0, errInvalidRange
+		}
+		return start, size - start, nil
+	default:
+		start, convErr1 := strconv.ParseInt(parts[0], 10, 64)
+		end, convErr2 := strconv.ParseInt(parts[1], 10, 64)
+		if convErr1 != nil || convErr2 != nil || start < 0 || end < start || start >= size {
+			return 0, 0, errInvalidRange
+		}
+		if end >= size {
+			end = size - 1
+		}
+		return start, end - start + 1, nil
+	}
+}
+
+func formatContentRange(offset, length, size int64) string {
+	return "bytes " + strconv.FormatInt(offset, 10) + "-" + strconv.FormatInt(offset+length-1, 10) + "/" + strconv.FormatInt(size, 10)
+}
+
+// checkPreconditions evaluates If-Match, If-None-Match, If-Modified-Since
+// and If-Unmodified-Since against metadata, returning the HTTP status code
+// to short-circuit with (304 or 412), or 0 when the request should proceed.
+func checkPreconditions(req *http.Request, metadata mstorage.ObjectMetadata) int {
+	if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && ifMatch != metadata.ETag {
+		return http.StatusPreconditionFailed
+	}
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" && ifNoneMatch == metadata.ETag {
+		return http.StatusNotModified
+	}
+	if since := req.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := time.Parse(time.RFC1123, since); err == nil && !metadata.Created.After(t) {
+			return http.StatusNotModified
+		}
+	}
+	if since := req.Header.Get("If-Unmodified-Since"); since != "" {
+		if t, err := time.Parse(time.RFC1123, since); err == nil && metadata.Created.After(t) {
+			return http.StatusPreconditionFailed
+		}
+	}
+	return 0
+}